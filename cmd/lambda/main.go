@@ -4,12 +4,26 @@ import (
 	"context"
 
 	"github.com/Tricarico1/go_watershed/internal/watershed"
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
 	"github.com/aws/aws-lambda-go/lambda"
 )
 
+var log = logging.Logger
+
+// monitor is built once, at init time, and reused across every warm
+// invocation in this execution environment - not rebuilt per request -
+// so its anomaly evaluator's rolling window keeps accumulating samples
+// across invocations instead of starting empty every 5 minutes. A cold
+// start still begins with an empty window; state isn't persisted
+// across execution environments.
+var monitor = watershed.NewMonitor()
+
 func handleRequest(ctx context.Context) error {
-	monitor := watershed.NewMonitor()
-	return monitor.RunOnce()
+	if err := monitor.RunOnce(ctx); err != nil {
+		log.Error("error in monitoring cycle", "error", err)
+		return err
+	}
+	return nil
 }
 
 func main() {
@@ -1,32 +1,152 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
-	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/Tricarico1/go_watershed/internal/watershed"
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
 )
 
+var log = logging.Logger
+
+// watchLogLevelReload flips the log level to whatever LOG_LEVEL is
+// currently set to whenever SIGHUP arrives, so operators can turn on
+// DEBUG logging without restarting the process.
+func watchLogLevelReload() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			logging.ReloadFromEnv()
+		}
+	}()
+}
+
+// serveLogLevelEndpoint exposes a tiny HTTP endpoint
+// (POST /log-level?level=debug) for environments where sending a
+// signal isn't convenient.
+func serveLogLevelEndpoint(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/log-level", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		level := r.URL.Query().Get("level")
+		logging.SetLevel(level)
+		log.Info("log level changed via HTTP", "level", level)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Error("log level endpoint stopped", "error", err)
+		}
+	}()
+}
+
+// serveSeriesAPI exposes a read-only debug endpoint
+// (GET /series?name=Temperature&from=<RFC3339>&to=<RFC3339>) that
+// reads historical samples back out of whichever sink supports
+// querying, so operators can check past values without re-hitting the
+// upstream data source. from/to default to the last hour when unset.
+func serveSeriesAPI(addr string, monitor *watershed.Monitor) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/series", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		to := time.Now()
+		if v := r.URL.Query().Get("to"); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "to must be RFC3339", http.StatusBadRequest)
+				return
+			}
+			to = parsed
+		}
+		from := to.Add(-time.Hour)
+		if v := r.URL.Query().Get("from"); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "from must be RFC3339", http.StatusBadRequest)
+				return
+			}
+			from = parsed
+		}
+
+		samples, ok, err := monitor.QuerySeries(r.Context(), name, from, to)
+		if err != nil {
+			log.Error("error querying series", "measurement", name, "error", err)
+			http.Error(w, "error querying series", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "no queryable sink configured", http.StatusNotImplemented)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(samples); err != nil {
+			log.Error("error encoding series response", "measurement", name, "error", err)
+		}
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Error("series API stopped", "error", err)
+		}
+	}()
+}
+
 func main() {
 	continuous := flag.Bool("continuous", false, "Run continuously every 5 minutes")
+	logLevelAddr := flag.String("log-level-addr", "", "Address to serve the /log-level reload endpoint on (continuous mode only)")
+	seriesAPIAddr := flag.String("series-api-addr", "", "Address to serve the read-only /series debug API on (continuous mode only)")
+	runTimeout := flag.Duration("run-timeout", 4*time.Minute, "Maximum time a single monitoring run may take before it's aborted")
 	flag.Parse()
 
+	watchLogLevelReload()
+
 	monitor := watershed.NewMonitor()
-	log.Println("Starting monitoring service...")
+	log.Info("starting monitoring service")
 
 	if *continuous {
+		if *logLevelAddr != "" {
+			serveLogLevelEndpoint(*logLevelAddr)
+		}
+		if *seriesAPIAddr != "" {
+			serveSeriesAPI(*seriesAPIAddr, monitor)
+		}
 		// Run in continuous mode (like the current version)
 		for {
-			if err := monitor.RunOnce(); err != nil {
-				log.Printf("Error in monitoring cycle: %v", err)
-			}
+			runOnceWithTimeout(monitor, *runTimeout)
 			time.Sleep(5 * time.Minute)
 		}
 	} else {
 		// Run once and exit (better for Lambda-like testing)
-		if err := monitor.RunOnce(); err != nil {
-			log.Printf("Error in monitoring cycle: %v", err)
-		}
+		runOnceWithTimeout(monitor, *runTimeout)
+	}
+}
+
+// runOnceWithTimeout bounds a single monitoring run at timeout, so an
+// upstream outage (a hung data source, a slow notifier) can't wedge
+// the process past the next scheduled run.
+func runOnceWithTimeout(monitor *watershed.Monitor, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := monitor.RunOnce(ctx); err != nil {
+		log.Error("error in monitoring cycle", "error", err)
 	}
 }
@@ -0,0 +1,247 @@
+// Package anomaly evaluates a measurement's rolling sample window
+// against a small set of composable rules — rate-of-change, rolling
+// z-score, and stuck-sensor detection — layered on top of Monitor's
+// static min/max threshold check.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/config"
+)
+
+// Sample is one rolling-window observation.
+type Sample struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// Finding describes a Rule that fired against a measurement's window.
+type Finding struct {
+	Kind    config.RuleKind
+	Value   float64
+	Message string
+}
+
+// Evaluator tracks a bounded rolling window of recent samples per
+// measurement and evaluates config.Rule values against it. It is not
+// safe for concurrent use; Monitor evaluates one measurement at a
+// time.
+type Evaluator struct {
+	capacity int
+	windows  map[string][]Sample
+	// stuckRuns tracks, per measurement and rule index (a measurement
+	// can have more than one Stuck rule), how many consecutive
+	// evaluations have seen variance below that rule's epsilon.
+	stuckRuns map[string]map[int]int
+}
+
+// NewEvaluator builds an Evaluator that retains up to capacity recent
+// samples per measurement. capacity<=0 falls back to 500, comfortably
+// above any SampleSize a measurement is likely to configure.
+func NewEvaluator(capacity int) *Evaluator {
+	if capacity <= 0 {
+		capacity = 500
+	}
+	return &Evaluator{
+		capacity:  capacity,
+		windows:   make(map[string][]Sample),
+		stuckRuns: make(map[string]map[int]int),
+	}
+}
+
+// Observe appends sample to measurement's rolling window, evicting the
+// oldest entry once the window is at capacity.
+func (e *Evaluator) Observe(measurement string, sample Sample) {
+	w := append(e.windows[measurement], sample)
+	if len(w) > e.capacity {
+		w = w[len(w)-e.capacity:]
+	}
+	e.windows[measurement] = w
+}
+
+// Evaluate runs each of rules against measurement's current window (so
+// Observe must be called first) and returns every rule that fired, in
+// rule order. An empty window, or a window whose only values are NaN,
+// produces no findings rather than an error; a window of one sample
+// can trip only a Threshold rule, since Rate, ZScore and Stuck each
+// need at least two samples.
+func (e *Evaluator) Evaluate(measurement string, rules []config.Rule) []Finding {
+	window := e.windows[measurement]
+
+	var findings []Finding
+	for i, rule := range rules {
+		var finding *Finding
+		switch rule.Kind {
+		case config.RuleThreshold:
+			finding = evalThreshold(rule, window)
+		case config.RuleRate:
+			finding = evalRate(rule, window)
+		case config.RuleZScore:
+			finding = evalZScore(rule, window)
+		case config.RuleStuck:
+			finding = e.evalStuck(measurement, i, rule, window)
+		}
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+	return findings
+}
+
+// latest returns the most recent non-NaN sample in window.
+func latest(window []Sample) (Sample, bool) {
+	for i := len(window) - 1; i >= 0; i-- {
+		if !math.IsNaN(window[i].Value) {
+			return window[i], true
+		}
+	}
+	return Sample{}, false
+}
+
+func evalThreshold(rule config.Rule, window []Sample) *Finding {
+	s, ok := latest(window)
+	if !ok {
+		return nil
+	}
+	if s.Value >= rule.Min && s.Value < rule.Max {
+		return nil
+	}
+	return &Finding{
+		Kind:    config.RuleThreshold,
+		Value:   s.Value,
+		Message: fmt.Sprintf("value %.2f outside [%.2f, %.2f)", s.Value, rule.Min, rule.Max),
+	}
+}
+
+// evalRate compares the latest sample to the oldest non-NaN sample
+// still within rule.Window of it, firing when they differ by more
+// than DeltaPerWindow in either direction.
+func evalRate(rule config.Rule, window []Sample) *Finding {
+	d, err := time.ParseDuration(rule.Window)
+	if err != nil || d <= 0 {
+		return nil
+	}
+
+	latestSample, ok := latest(window)
+	if !ok {
+		return nil
+	}
+	cutoff := latestSample.Timestamp.Add(-d)
+
+	var baseline Sample
+	found := false
+	for _, s := range window {
+		if math.IsNaN(s.Value) || s.Timestamp.Before(cutoff) || s.Timestamp.Equal(latestSample.Timestamp) {
+			continue
+		}
+		baseline = s
+		found = true
+		break
+	}
+	if !found {
+		return nil
+	}
+
+	delta := latestSample.Value - baseline.Value
+	if math.Abs(delta) <= rule.DeltaPerWindow {
+		return nil
+	}
+	return &Finding{
+		Kind:    config.RuleRate,
+		Value:   delta,
+		Message: fmt.Sprintf("changed %.2f over %s (limit %.2f)", delta, rule.Window, rule.DeltaPerWindow),
+	}
+}
+
+func evalZScore(rule config.Rule, window []Sample) *Finding {
+	values := recentValues(window, rule.SampleSize)
+	if len(values) < 2 {
+		return nil
+	}
+
+	mean, stddev := meanStddev(values)
+	if stddev == 0 {
+		return nil
+	}
+
+	latestValue := values[len(values)-1]
+	z := (latestValue - mean) / stddev
+	if math.Abs(z) <= rule.ZThreshold {
+		return nil
+	}
+	return &Finding{
+		Kind:    config.RuleZScore,
+		Value:   z,
+		Message: fmt.Sprintf("z-score %.2f exceeds %.2f (mean %.2f, stddev %.2f)", z, rule.ZThreshold, mean, stddev),
+	}
+}
+
+// evalStuck fires once a measurement's rolling variance has stayed
+// below rule.Epsilon for rule.ConsecutiveWindows evaluations in a
+// row; the run counter resets as soon as variance rises back above
+// epsilon.
+func (e *Evaluator) evalStuck(measurement string, ruleIdx int, rule config.Rule, window []Sample) *Finding {
+	values := recentValues(window, rule.SampleSize)
+	if len(values) < 2 {
+		return nil
+	}
+
+	_, stddev := meanStddev(values)
+	variance := stddev * stddev
+
+	if e.stuckRuns[measurement] == nil {
+		e.stuckRuns[measurement] = make(map[int]int)
+	}
+
+	if variance >= rule.Epsilon {
+		e.stuckRuns[measurement][ruleIdx] = 0
+		return nil
+	}
+
+	e.stuckRuns[measurement][ruleIdx]++
+	run := e.stuckRuns[measurement][ruleIdx]
+	if run < rule.ConsecutiveWindows {
+		return nil
+	}
+	return &Finding{
+		Kind:    config.RuleStuck,
+		Value:   variance,
+		Message: fmt.Sprintf("variance %.6f below %.6f for %d consecutive windows", variance, rule.Epsilon, run),
+	}
+}
+
+// recentValues returns the up-to-n most recent non-NaN values in
+// window, oldest first. n<=0 means "all of them".
+func recentValues(window []Sample, n int) []float64 {
+	var values []float64
+	for _, s := range window {
+		if math.IsNaN(s.Value) {
+			continue
+		}
+		values = append(values, s.Value)
+	}
+	if n > 0 && len(values) > n {
+		values = values[len(values)-n:]
+	}
+	return values
+}
+
+// meanStddev returns the population mean and standard deviation of
+// values.
+func meanStddev(values []float64) (mean, stddev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var sumSq float64
+	for _, v := range values {
+		d := v - mean
+		sumSq += d * d
+	}
+	stddev = math.Sqrt(sumSq / float64(len(values)))
+	return mean, stddev
+}
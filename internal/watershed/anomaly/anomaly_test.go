@@ -0,0 +1,191 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/config"
+)
+
+func observe(e *Evaluator, measurement string, values []float64, start time.Time, step time.Duration) {
+	for i, v := range values {
+		e.Observe(measurement, Sample{Value: v, Timestamp: start.Add(time.Duration(i) * step)})
+	}
+}
+
+func TestEvaluateEmptyWindow(t *testing.T) {
+	e := NewEvaluator(10)
+	rules := []config.Rule{
+		{Kind: config.RuleThreshold, Min: 0, Max: 100},
+		{Kind: config.RuleRate, Window: "15m", DeltaPerWindow: 10},
+		{Kind: config.RuleZScore, SampleSize: 5, ZThreshold: 2},
+		{Kind: config.RuleStuck, SampleSize: 5, Epsilon: 0.01, ConsecutiveWindows: 3},
+	}
+
+	if findings := e.Evaluate("Water depth", rules); len(findings) != 0 {
+		t.Fatalf("expected no findings against an empty window, got %v", findings)
+	}
+}
+
+func TestEvaluateSingleSample(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	e.Observe("Temperature", Sample{Value: 200, Timestamp: now})
+
+	rules := []config.Rule{
+		{Kind: config.RuleThreshold, Min: 0, Max: 26},
+		{Kind: config.RuleRate, Window: "15m", DeltaPerWindow: 5},
+		{Kind: config.RuleZScore, SampleSize: 5, ZThreshold: 2},
+		{Kind: config.RuleStuck, SampleSize: 5, Epsilon: 0.01, ConsecutiveWindows: 3},
+	}
+
+	findings := e.Evaluate("Temperature", rules)
+	if len(findings) != 1 || findings[0].Kind != config.RuleThreshold {
+		t.Fatalf("expected only the threshold rule to fire on a single out-of-range sample, got %v", findings)
+	}
+}
+
+func TestEvaluateNaNSamplesSkipped(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	observe(e, "Turbidity", []float64{10, math.NaN(), 12, math.NaN()}, now, time.Minute)
+
+	rules := []config.Rule{{Kind: config.RuleThreshold, Min: 0, Max: 100}}
+	if findings := e.Evaluate("Turbidity", rules); len(findings) != 0 {
+		t.Fatalf("expected the last non-NaN sample (12) to be in range, got %v", findings)
+	}
+
+	// An all-NaN window should also produce no findings, not a crash.
+	e2 := NewEvaluator(10)
+	observe(e2, "Turbidity", []float64{math.NaN(), math.NaN()}, now, time.Minute)
+	if findings := e2.Evaluate("Turbidity", rules); len(findings) != 0 {
+		t.Fatalf("expected an all-NaN window to produce no findings, got %v", findings)
+	}
+}
+
+func TestThresholdRule(t *testing.T) {
+	e := NewEvaluator(10)
+	e.Observe("Battery voltage", Sample{Value: -1, Timestamp: time.Now()})
+
+	rules := []config.Rule{{Kind: config.RuleThreshold, Min: 0, Max: 5}}
+	findings := e.Evaluate("Battery voltage", rules)
+	if len(findings) != 1 || findings[0].Kind != config.RuleThreshold {
+		t.Fatalf("expected a threshold finding for an out-of-range value, got %v", findings)
+	}
+
+	e.Observe("Battery voltage", Sample{Value: 3.3, Timestamp: time.Now()})
+	if findings := e.Evaluate("Battery voltage", rules); len(findings) != 0 {
+		t.Fatalf("expected no finding for an in-range value, got %v", findings)
+	}
+}
+
+func TestRateRuleFlashFlood(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	// Water depth climbs 50cm over 9 minutes - a flash flood.
+	observe(e, "Water depth", []float64{10, 20, 35, 60}, now, 3*time.Minute)
+
+	rules := []config.Rule{{Kind: config.RuleRate, Window: "15m", DeltaPerWindow: 30}}
+	findings := e.Evaluate("Water depth", rules)
+	if len(findings) != 1 || findings[0].Kind != config.RuleRate {
+		t.Fatalf("expected a rate finding for a 50cm rise, got %v", findings)
+	}
+	if findings[0].Value != 50 {
+		t.Errorf("expected delta 50, got %v", findings[0].Value)
+	}
+}
+
+func TestRateRuleIgnoresSamplesOutsideWindow(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	// A big jump, but it happened an hour ago - outside the 15m window.
+	e.Observe("Water depth", Sample{Value: 10, Timestamp: now.Add(-time.Hour)})
+	e.Observe("Water depth", Sample{Value: 60, Timestamp: now})
+
+	rules := []config.Rule{{Kind: config.RuleRate, Window: "15m", DeltaPerWindow: 30}}
+	if findings := e.Evaluate("Water depth", rules); len(findings) != 0 {
+		t.Fatalf("expected no rate finding when the baseline sample is outside the window, got %v", findings)
+	}
+}
+
+func TestZScoreRule(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	observe(e, "Electrical conductivity", []float64{100, 102, 98, 101, 99, 400}, now, time.Minute)
+
+	rules := []config.Rule{{Kind: config.RuleZScore, SampleSize: 6, ZThreshold: 2}}
+	findings := e.Evaluate("Electrical conductivity", rules)
+	if len(findings) != 1 || findings[0].Kind != config.RuleZScore {
+		t.Fatalf("expected a z-score finding for an outlier sample, got %v", findings)
+	}
+}
+
+func TestZScoreRuleConstantSeriesNoFinding(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	observe(e, "Relative humidity", []float64{50, 50, 50, 50}, now, time.Minute)
+
+	rules := []config.Rule{{Kind: config.RuleZScore, SampleSize: 4, ZThreshold: 2}}
+	if findings := e.Evaluate("Relative humidity", rules); len(findings) != 0 {
+		t.Fatalf("expected no finding when stddev is zero, got %v", findings)
+	}
+}
+
+func TestStuckRuleFiresAfterConsecutiveWindows(t *testing.T) {
+	e := NewEvaluator(20)
+	now := time.Now()
+	rules := []config.Rule{{Kind: config.RuleStuck, SampleSize: 3, Epsilon: 0.01, ConsecutiveWindows: 3}}
+
+	// Feed a flat series one sample at a time, evaluating after each
+	// one the way Monitor would.
+	flat := []float64{5, 5, 5, 5, 5}
+	var findings []Finding
+	for i, v := range flat {
+		e.Observe("Percent full scale", Sample{Value: v, Timestamp: now.Add(time.Duration(i) * time.Minute)})
+		findings = e.Evaluate("Percent full scale", rules)
+	}
+
+	if len(findings) != 1 || findings[0].Kind != config.RuleStuck {
+		t.Fatalf("expected a stuck finding once variance has stayed near zero for 3 consecutive windows, got %v", findings)
+	}
+}
+
+func TestStuckRuleResetsWhenValueMoves(t *testing.T) {
+	e := NewEvaluator(20)
+	now := time.Now()
+	rules := []config.Rule{{Kind: config.RuleStuck, SampleSize: 3, Epsilon: 0.01, ConsecutiveWindows: 3}}
+
+	series := []float64{5, 5, 5, 12, 12, 12}
+	var lastFindings []Finding
+	for i, v := range series {
+		e.Observe("Percent full scale", Sample{Value: v, Timestamp: now.Add(time.Duration(i) * time.Minute)})
+		lastFindings = e.Evaluate("Percent full scale", rules)
+	}
+
+	// The jump to 12 should have reset the consecutive-window count,
+	// so after it only the final [12, 12, 12] window counts as flat -
+	// one of the three consecutive windows needed to trip the rule.
+	if len(lastFindings) != 0 {
+		t.Fatalf("expected the stuck counter to reset after the value changed, got %v", lastFindings)
+	}
+}
+
+func TestEvaluateRunsRulesInOrder(t *testing.T) {
+	e := NewEvaluator(10)
+	now := time.Now()
+	observe(e, "Water depth", []float64{10, 20, 35, 60}, now, 3*time.Minute)
+
+	rules := []config.Rule{
+		{Kind: config.RuleThreshold, Min: 0, Max: 50},
+		{Kind: config.RuleRate, Window: "15m", DeltaPerWindow: 30},
+	}
+
+	findings := e.Evaluate("Water depth", rules)
+	if len(findings) != 2 {
+		t.Fatalf("expected both rules to fire, got %v", findings)
+	}
+	if findings[0].Kind != config.RuleThreshold || findings[1].Kind != config.RuleRate {
+		t.Fatalf("expected findings in rule order (threshold, rate), got %v", findings)
+	}
+}
@@ -0,0 +1,19 @@
+package anomaly
+
+import (
+	"os"
+	"strconv"
+)
+
+// NewEvaluatorFromEnv builds an Evaluator sized by
+// ANOMALY_WINDOW_CAPACITY, falling back to NewEvaluator's default when
+// it's unset or unparseable.
+func NewEvaluatorFromEnv() *Evaluator {
+	capacity := 0
+	if v := os.Getenv("ANOMALY_WINDOW_CAPACITY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			capacity = n
+		}
+	}
+	return NewEvaluator(capacity)
+}
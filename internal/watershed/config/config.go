@@ -0,0 +1,186 @@
+// Package config loads the measurement/threshold/routing
+// configuration that drives Monitor, replacing the old
+// env-var-per-measurement threshold lookup.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultSuppressFor = 12 * time.Hour
+
+// RouteRule sends alerts to Notifiers when a measurement's labels
+// match every key/value pair in Labels.
+type RouteRule struct {
+	Labels    map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Notifiers []string          `json:"notifiers" yaml:"notifiers"`
+}
+
+// RuleKind names which anomaly check a Rule performs.
+type RuleKind string
+
+const (
+	// RuleThreshold fires when the latest sample falls outside
+	// [Min, Max), independent of the measurement's own Min/Max bound
+	// (e.g. a softer "warning" band around the hard limit).
+	RuleThreshold RuleKind = "threshold"
+	// RuleRate fires when the value changes by more than
+	// DeltaPerWindow within Window, e.g. water depth rising too fast
+	// to be anything but a flash flood.
+	RuleRate RuleKind = "rate"
+	// RuleZScore fires when the rolling z-score over the last
+	// SampleSize samples exceeds ZThreshold in magnitude.
+	RuleZScore RuleKind = "zscore"
+	// RuleStuck fires when the rolling variance over the last
+	// SampleSize samples stays below Epsilon for ConsecutiveWindows
+	// evaluations in a row, suggesting a sensor reporting the same
+	// value rather than a genuinely stable reading.
+	RuleStuck RuleKind = "stuck"
+)
+
+// Rule is one anomaly check run against a measurement's rolling
+// sample window by the anomaly package, in addition to the
+// measurement's own Min/Max/Hysteresis bound. Only the fields
+// relevant to Kind need to be set; the rest are ignored.
+type Rule struct {
+	Kind RuleKind `json:"kind" yaml:"kind"`
+
+	// Threshold
+	Min float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max float64 `json:"max,omitempty" yaml:"max,omitempty"`
+
+	// Rate: Window is a time.ParseDuration string, e.g. "15m".
+	Window         string  `json:"window,omitempty" yaml:"window,omitempty"`
+	DeltaPerWindow float64 `json:"delta_per_window,omitempty" yaml:"delta_per_window,omitempty"`
+
+	// ZScore
+	SampleSize int     `json:"sample_size,omitempty" yaml:"sample_size,omitempty"`
+	ZThreshold float64 `json:"z_threshold,omitempty" yaml:"z_threshold,omitempty"`
+
+	// Stuck
+	Epsilon            float64 `json:"epsilon,omitempty" yaml:"epsilon,omitempty"`
+	ConsecutiveWindows int     `json:"consecutive_windows,omitempty" yaml:"consecutive_windows,omitempty"`
+}
+
+// Measurement is the full configuration for one monitored
+// measurement: its acceptable range plus the alias, labels and
+// routing needed to dispatch alerts the way the team that owns it
+// wants them delivered.
+type Measurement struct {
+	Name        string            `json:"name" yaml:"name"`
+	Alias       string            `json:"alias,omitempty" yaml:"alias,omitempty"`
+	DisplayName string            `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Min         float64           `json:"min" yaml:"min"`
+	Max         float64           `json:"max" yaml:"max"`
+	// SuppressFor is a time.ParseDuration string, e.g. "12h". Empty
+	// falls back to the historical 12-hour suppression window.
+	SuppressFor string `json:"suppress_for,omitempty" yaml:"suppress_for,omitempty"`
+	// Hysteresis widens the acceptable range by this amount while the
+	// most recent alert is still within SuppressionWindow, so a value
+	// oscillating right at the threshold doesn't re-trigger on every
+	// sample.
+	Hysteresis float64     `json:"hysteresis,omitempty" yaml:"hysteresis,omitempty"`
+	Routes     []RouteRule `json:"routes,omitempty" yaml:"routes,omitempty"`
+	// Rules are additional rolling-window anomaly checks (rate of
+	// change, z-score, stuck sensor) evaluated alongside the static
+	// Min/Max bound above.
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// SuppressionWindow returns how long to wait between repeat alerts
+// for this measurement.
+func (m Measurement) SuppressionWindow() time.Duration {
+	if m.SuppressFor == "" {
+		return defaultSuppressFor
+	}
+	d, err := time.ParseDuration(m.SuppressFor)
+	if err != nil {
+		return defaultSuppressFor
+	}
+	return d
+}
+
+// Label returns the human-friendly name to use in alert text: the
+// configured display name, falling back to the measurement's alias,
+// falling back to its raw Name.
+func (m Measurement) Label() string {
+	if m.DisplayName != "" {
+		return m.DisplayName
+	}
+	if m.Alias != "" {
+		return m.Alias
+	}
+	return m.Name
+}
+
+// Config is the full set of monitored measurements.
+type Config struct {
+	Measurements []Measurement `json:"measurements" yaml:"measurements"`
+}
+
+// Lookup finds a measurement's config by its source name or its
+// configured alias.
+func (c *Config) Lookup(name string) (Measurement, bool) {
+	for _, m := range c.Measurements {
+		if m.Name == name || (m.Alias != "" && m.Alias == name) {
+			return m, true
+		}
+	}
+	return Measurement{}, false
+}
+
+// Default reproduces the thresholds this tool shipped with before
+// configuration moved to file, for deployments that haven't set up a
+// config file yet.
+func Default() *Config {
+	return &Config{Measurements: []Measurement{
+		{Name: "Water depth", Min: 0, Max: 1000},
+		{Name: "Temperature", Min: -20, Max: 26},
+		{Name: "Electrical conductivity", Min: 0, Max: 600},
+		{Name: "Turbidity", Min: 0, Max: 150},
+		{Name: "Battery voltage", Min: 0, Max: 5},
+		{Name: "Percent full scale", Min: 0, Max: 101},
+		{Name: "Relative humidity", Min: 0, Max: 100},
+	}}
+}
+
+// Load reads a measurement config from a YAML or JSON file, chosen by
+// its extension (.yaml/.yml vs everything else).
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("error parsing yaml config: %v", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("error parsing json config: %v", err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// FromEnv loads the config file named by WATERSHED_CONFIG_FILE, or
+// falls back to Default when it's unset.
+func FromEnv() (*Config, error) {
+	path := os.Getenv("WATERSHED_CONFIG_FILE")
+	if path == "" {
+		return Default(), nil
+	}
+	return Load(path)
+}
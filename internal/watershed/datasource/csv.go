@@ -0,0 +1,106 @@
+package datasource
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+// CSVHTTP reads a generic "measurement,value,timestamp" CSV document
+// served over HTTP, for deployments whose loggers only know how to
+// push a flat file rather than speak a network-specific API.
+type CSVHTTP struct {
+	url    string
+	client *httpx.Client
+}
+
+// NewCSVHTTPFromEnv builds a CSVHTTP source from CSV_URL. It returns
+// ok=false when the variable is unset.
+func NewCSVHTTPFromEnv() (*CSVHTTP, bool) {
+	url := os.Getenv("CSV_URL")
+	if url == "" {
+		return nil, false
+	}
+	return &CSVHTTP{url: url, client: httpx.NewClientFromEnv()}, true
+}
+
+func (s *CSVHTTP) Name() string { return "csv" }
+
+func (s *CSVHTTP) fetchRows(ctx context.Context) ([][]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("http request", "source", s.Name(), "method", req.Method, "url", s.url)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	reader := csv.NewReader(resp.Body)
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv: %v", err)
+	}
+	if len(rows) > 0 {
+		rows = rows[1:] // drop the header row
+	}
+	return rows, nil
+}
+
+// ListMeasurements distinguishes measurements by the first column of
+// each CSV row; the ID is the measurement name itself.
+func (s *CSVHTTP) ListMeasurements(ctx context.Context) ([]Measurement, error) {
+	rows, err := s.fetchRows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing csv measurements: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var measurements []Measurement
+	for _, row := range rows {
+		if len(row) < 1 || seen[row[0]] {
+			continue
+		}
+		seen[row[0]] = true
+		measurements = append(measurements, Measurement{Name: row[0], ID: row[0]})
+	}
+
+	return measurements, nil
+}
+
+func (s *CSVHTTP) FetchSeries(ctx context.Context, id string, since time.Time) ([]Sample, error) {
+	rows, err := s.fetchRows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching csv series for %s: %v", id, err)
+	}
+
+	var samples []Sample
+	for _, row := range rows {
+		if len(row) < 3 || row[0] != id {
+			continue
+		}
+		value, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, row[2])
+		if err != nil {
+			continue
+		}
+		if t.Before(since) {
+			continue
+		}
+		samples = append(samples, Sample{Value: value, Timestamp: t})
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,32 @@
+// Package datasource defines the sensor-network abstraction that lets
+// the alerting engine be pointed at more than just
+// monitormywatershed.org.
+package datasource
+
+import (
+	"context"
+	"time"
+)
+
+// Measurement identifies one monitored variable exposed by a
+// DataSource, e.g. "Water depth".
+type Measurement struct {
+	Name string
+	ID   string
+}
+
+// Sample is a single timestamped reading for a measurement.
+type Sample struct {
+	Measurement string
+	Value       float64
+	Timestamp   time.Time
+}
+
+// DataSource is a sensor network that can be polled for measurements
+// and their recent samples.
+type DataSource interface {
+	// Name identifies the source, e.g. "monitormywatershed".
+	Name() string
+	ListMeasurements(ctx context.Context) ([]Measurement, error)
+	FetchSeries(ctx context.Context, id string, since time.Time) ([]Sample, error)
+}
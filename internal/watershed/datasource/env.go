@@ -0,0 +1,21 @@
+package datasource
+
+// FromEnv builds the set of DataSources configured via environment
+// variables. monitormywatershed.org is always included to preserve
+// the tool's original default behavior; InfluxDB and CSV-over-HTTP
+// are added when their variables are present.
+func FromEnv() []DataSource {
+	sources := []DataSource{NewMonitorMyWatershedFromEnv()}
+	log.Info("data source configured", "source", "monitormywatershed")
+
+	if s, ok := NewInfluxDBFromEnv(); ok {
+		sources = append(sources, s)
+		log.Info("data source configured", "source", "influxdb")
+	}
+	if s, ok := NewCSVHTTPFromEnv(); ok {
+		sources = append(sources, s)
+		log.Info("data source configured", "source", "csv")
+	}
+
+	return sources
+}
@@ -0,0 +1,144 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+// InfluxDB reads measurements back out of an InfluxDB instance fed by
+// Telegraf, for watershed deployments that already run a
+// Telegraf/InfluxDB stack instead of monitormywatershed.org.
+type InfluxDB struct {
+	addr     string
+	database string
+	token    string
+	client   *httpx.Client
+}
+
+// NewInfluxDBFromEnv builds an InfluxDB source from INFLUXDB_ADDR,
+// INFLUXDB_DATABASE and INFLUXDB_TOKEN. It returns ok=false when addr
+// or database is unset.
+func NewInfluxDBFromEnv() (*InfluxDB, bool) {
+	addr := os.Getenv("INFLUXDB_ADDR")
+	database := os.Getenv("INFLUXDB_DATABASE")
+	if addr == "" || database == "" {
+		return nil, false
+	}
+	return &InfluxDB{
+		addr:     strings.TrimRight(addr, "/"),
+		database: database,
+		token:    os.Getenv("INFLUXDB_TOKEN"),
+		client:   httpx.NewClientFromEnv(),
+	}, true
+}
+
+func (s *InfluxDB) Name() string { return "influxdb" }
+
+func (s *InfluxDB) authHeader(req *http.Request) {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
+	}
+}
+
+func (s *InfluxDB) query(ctx context.Context, q string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.addr+"/query", nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("db", s.database)
+	query.Set("q", q)
+	req.URL.RawQuery = query.Encode()
+	s.authHeader(req)
+
+	log.Debug("http request", "source", s.Name(), "method", req.Method, "url", req.URL.String())
+	return s.client.Do(req)
+}
+
+type influxQueryResponse struct {
+	Results []struct {
+		Series []struct {
+			Name    string          `json:"name"`
+			Columns []string        `json:"columns"`
+			Values  [][]interface{} `json:"values"`
+		} `json:"series"`
+	} `json:"results"`
+}
+
+// ListMeasurements enumerates the Telegraf measurement names stored in
+// the configured database; the measurement ID is the name itself
+// since Influx addresses series by name, not a numeric result ID.
+func (s *InfluxDB) ListMeasurements(ctx context.Context) ([]Measurement, error) {
+	resp, err := s.query(ctx, "SHOW MEASUREMENTS")
+	if err != nil {
+		return nil, fmt.Errorf("error listing influxdb measurements: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed influxQueryResponse
+	if err := decodeJSON(resp.Body, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing influxdb response: %v", err)
+	}
+
+	var measurements []Measurement
+	for _, result := range parsed.Results {
+		for _, series := range result.Series {
+			for _, row := range series.Values {
+				if len(row) == 0 {
+					continue
+				}
+				name, ok := row[0].(string)
+				if !ok {
+					continue
+				}
+				measurements = append(measurements, Measurement{Name: name, ID: name})
+			}
+		}
+	}
+
+	return measurements, nil
+}
+
+func (s *InfluxDB) FetchSeries(ctx context.Context, id string, since time.Time) ([]Sample, error) {
+	q := fmt.Sprintf(`SELECT "value" FROM "%s" WHERE time >= '%s'`,
+		id, since.UTC().Format(time.RFC3339))
+	resp, err := s.query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching influxdb series for %s: %v", id, err)
+	}
+	defer resp.Body.Close()
+
+	var parsed influxQueryResponse
+	if err := decodeJSON(resp.Body, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing influxdb series for %s: %v", id, err)
+	}
+
+	var samples []Sample
+	for _, result := range parsed.Results {
+		for _, series := range result.Series {
+			for _, row := range series.Values {
+				if len(row) < 2 {
+					continue
+				}
+				ts, ok1 := row[0].(string)
+				value, ok2 := row[1].(float64)
+				if !ok1 || !ok2 {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, ts)
+				if err != nil {
+					continue
+				}
+				samples = append(samples, Sample{Value: value, Timestamp: t})
+			}
+		}
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,14 @@
+package datasource
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
+)
+
+var log = logging.Logger
+
+func decodeJSON(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,141 @@
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+const (
+	monitorMyWatershedBaseURL = "https://monitormywatershed.org/dataloader/ajax/"
+)
+
+// MonitorMyWatershed polls monitormywatershed.org's dataloader AJAX
+// endpoint, the site this tool was originally written against.
+type MonitorMyWatershed struct {
+	samplingCode string
+	client       *httpx.Client
+}
+
+// NewMonitorMyWatershedFromEnv builds a MonitorMyWatershed source from
+// MMW_SAMPLING_CODE, defaulting to the station this tool was written
+// for.
+func NewMonitorMyWatershedFromEnv() *MonitorMyWatershed {
+	samplingCode := os.Getenv("MMW_SAMPLING_CODE")
+	if samplingCode == "" {
+		samplingCode = "MSPL2S"
+	}
+	return &MonitorMyWatershed{samplingCode: samplingCode, client: httpx.NewClientFromEnv()}
+}
+
+func (s *MonitorMyWatershed) Name() string { return "monitormywatershed" }
+
+type timeSeriesData struct {
+	ValueID             map[string]int64   `json:"valueid"`
+	DataValue           map[string]float64 `json:"datavalue"`
+	ValueDateTime       map[string]int64   `json:"valuedatetime"`
+	ValueDateTimeOffset map[string]int     `json:"valuedatetimeutcoffset"`
+}
+
+func (s *MonitorMyWatershed) makePostRequest(ctx context.Context, formValues map[string]string) ([]byte, error) {
+	formData := make(url.Values)
+	for key, value := range formValues {
+		formData.Set(key, value)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", monitorMyWatershedBaseURL, bytes.NewBufferString(formData.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://monitormywatershed.org")
+	req.Header.Set("Referer", "https://monitormywatershed.org/tsv/")
+
+	log.Debug("http request", "source", s.Name(), "method", req.Method, "url", monitorMyWatershedBaseURL)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	log.Debug("http response", "source", s.Name(), "status", resp.StatusCode)
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *MonitorMyWatershed) ListMeasurements(ctx context.Context) ([]Measurement, error) {
+	data := map[string]string{
+		"request_data": fmt.Sprintf(`{"method":"get_sampling_feature_metadata","sampling_feature_code":"%s"}`, s.samplingCode),
+	}
+	response, err := s.makePostRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonStr string
+	if err := json.Unmarshal(response, &jsonStr); err != nil {
+		return nil, fmt.Errorf("first unmarshal error: %v", err)
+	}
+
+	var results []map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &results); err != nil {
+		return nil, fmt.Errorf("second unmarshal error: %v", err)
+	}
+
+	var measurements []Measurement
+	for _, result := range results {
+		name, ok1 := result["variablenamecv"].(string)
+		resultID, ok2 := result["resultid"].(float64)
+		if !ok1 || !ok2 {
+			continue
+		}
+		measurements = append(measurements, Measurement{
+			Name: name,
+			ID:   fmt.Sprintf("%d", int(resultID)),
+		})
+	}
+
+	return measurements, nil
+}
+
+func (s *MonitorMyWatershed) FetchSeries(ctx context.Context, id string, since time.Time) ([]Sample, error) {
+	data := map[string]string{
+		"request_data": fmt.Sprintf(`{"method":"get_result_timeseries","resultid":"%s","start_date":"%s","end_date":"%s"}`,
+			id, since.Format(time.RFC3339), time.Now().Format(time.RFC3339)),
+	}
+	response, err := s.makePostRequest(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching series for %s: %v", id, err)
+	}
+
+	var jsonStr string
+	if err := json.Unmarshal(response, &jsonStr); err != nil {
+		return nil, fmt.Errorf("error parsing response for %s: %v", id, err)
+	}
+
+	var ts timeSeriesData
+	if err := json.Unmarshal([]byte(jsonStr), &ts); err != nil {
+		return nil, fmt.Errorf("error parsing data for %s: %v", id, err)
+	}
+
+	samples := make([]Sample, 0, len(ts.DataValue))
+	for key := range ts.DataValue {
+		samples = append(samples, Sample{
+			Value:     ts.DataValue[key],
+			Timestamp: time.Unix(ts.ValueDateTime[key]/1000, 0),
+		})
+	}
+	// The response is keyed by map, so restore chronological order.
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+	return samples, nil
+}
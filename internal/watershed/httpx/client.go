@@ -0,0 +1,243 @@
+// Package httpx provides the resilient HTTP client shared by every
+// data source and notifier: it layers retries with exponential
+// backoff and a circuit breaker on top of the stdlib client, and
+// honors the caller's context deadline/cancellation between attempts
+// instead of retrying blindly past it.
+package httpx
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
+)
+
+var log = logging.Logger
+
+// Config controls a Client's timeout, retry/backoff schedule and
+// circuit breaker.
+type Config struct {
+	// Timeout bounds a single attempt, including connection setup.
+	Timeout time.Duration
+	// MaxRetries is the number of retries attempted after an initial
+	// failure (so MaxRetries+1 attempts total).
+	MaxRetries int
+	// BaseDelay and MaxDelay bound the exponential backoff between
+	// retries; actual delay is chosen uniformly in [0, cap].
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// BreakerThreshold is how many consecutive failures trip the
+	// breaker; BreakerCooldown is how long it then stays open before
+	// the next request is allowed through.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultConfig is used when no HTTP_* environment variables are set.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          10 * time.Second,
+		MaxRetries:       3,
+		BaseDelay:        250 * time.Millisecond,
+		MaxDelay:         5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// ConfigFromEnv builds a Config from HTTP_TIMEOUT_SECONDS,
+// HTTP_MAX_RETRIES, HTTP_BASE_DELAY_MS, HTTP_MAX_DELAY_MS,
+// HTTP_BREAKER_THRESHOLD and HTTP_BREAKER_COOLDOWN_SECONDS, falling
+// back to DefaultConfig field-by-field for anything unset or
+// unparseable.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if v, ok := envSeconds("HTTP_TIMEOUT_SECONDS"); ok {
+		cfg.Timeout = v
+	}
+	if v, ok := envInt("HTTP_MAX_RETRIES"); ok {
+		cfg.MaxRetries = v
+	}
+	if v, ok := envMillis("HTTP_BASE_DELAY_MS"); ok {
+		cfg.BaseDelay = v
+	}
+	if v, ok := envMillis("HTTP_MAX_DELAY_MS"); ok {
+		cfg.MaxDelay = v
+	}
+	if v, ok := envInt("HTTP_BREAKER_THRESHOLD"); ok {
+		cfg.BreakerThreshold = v
+	}
+	if v, ok := envSeconds("HTTP_BREAKER_COOLDOWN_SECONDS"); ok {
+		cfg.BreakerCooldown = v
+	}
+	return cfg
+}
+
+func envInt(key string) (int, bool) {
+	n, err := strconv.Atoi(os.Getenv(key))
+	return n, err == nil
+}
+
+func envSeconds(key string) (time.Duration, bool) {
+	n, ok := envInt(key)
+	return time.Duration(n) * time.Second, ok
+}
+
+func envMillis(key string) (time.Duration, bool) {
+	n, ok := envInt(key)
+	return time.Duration(n) * time.Millisecond, ok
+}
+
+// BreakerEvent describes a circuit breaker state transition on one
+// Client.
+type BreakerEvent struct {
+	// Host is the request host the breaker tripped for, e.g.
+	// "events.pagerduty.com".
+	Host string
+	// Open is true when the breaker just tripped open, false when it
+	// just closed after a successful request.
+	Open bool
+	// OpenUntil is when the breaker will next allow a request through.
+	// It's the zero value when Open is false.
+	OpenUntil time.Time
+}
+
+// OnBreakerChange, if set, is called whenever any Client's circuit
+// breaker opens or closes. Monitor wires this up once, in NewMonitor,
+// to turn a breaker trip into a notify.Alert so a sustained upstream
+// outage shows up as an alert rather than only in logs - mirroring
+// the package-level logger convention the rest of this codebase uses
+// for process-wide hooks.
+var OnBreakerChange func(BreakerEvent)
+
+// Client is a drop-in replacement for *http.Client's Do method that
+// adds retries, exponential backoff and a circuit breaker. It's safe
+// for concurrent use.
+type Client struct {
+	inner *http.Client
+	cfg   Config
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	openUntil           time.Time
+	// open mirrors whether the breaker is currently tripped. It's
+	// tracked separately from openUntil because openUntil alone can't
+	// tell OnBreakerChange apart from "never tripped" once the
+	// cooldown has elapsed but no call has observed that yet.
+	open bool
+}
+
+// NewClient builds a Client from cfg.
+func NewClient(cfg Config) *Client {
+	return &Client{inner: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
+}
+
+// NewClientFromEnv builds a Client configured via ConfigFromEnv.
+func NewClientFromEnv() *Client {
+	return NewClient(ConfigFromEnv())
+}
+
+// breakerOpen reports whether recent consecutive failures have
+// tripped the circuit breaker and its cooldown hasn't elapsed yet.
+func (c *Client) breakerOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.openUntil.IsZero() && time.Now().Before(c.openUntil)
+}
+
+func (c *Client) recordSuccess(host string) {
+	c.mu.Lock()
+	wasOpen := c.open
+	c.consecutiveFailures = 0
+	c.openUntil = time.Time{}
+	c.open = false
+	c.mu.Unlock()
+
+	if wasOpen && OnBreakerChange != nil {
+		OnBreakerChange(BreakerEvent{Host: host, Open: false})
+	}
+}
+
+func (c *Client) recordFailure(host string) {
+	c.mu.Lock()
+	wasOpen := c.open
+	c.consecutiveFailures++
+	if c.consecutiveFailures >= c.cfg.BreakerThreshold {
+		c.openUntil = time.Now().Add(c.cfg.BreakerCooldown)
+		c.open = true
+	}
+	nowOpen := c.open
+	openUntil := c.openUntil
+	c.mu.Unlock()
+
+	if !wasOpen && nowOpen && OnBreakerChange != nil {
+		OnBreakerChange(BreakerEvent{Host: host, Open: true, OpenUntil: openUntil})
+	}
+}
+
+// backoff returns the delay before the given retry attempt (1 for the
+// first retry, 2 for the second, ...): exponential in attempt, capped
+// at MaxDelay, with full jitter so concurrent callers don't retry in
+// lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.BaseDelay * time.Duration(uint64(1)<<uint(attempt-1))
+	if ceiling <= 0 || ceiling > c.cfg.MaxDelay {
+		ceiling = c.cfg.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// Do sends req, retrying transport errors and 5xx responses with
+// exponential backoff up to cfg.MaxRetries times, failing fast while
+// the circuit breaker is open, and aborting early if req's context is
+// canceled or its deadline passes rather than sleeping past it.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.breakerOpen() {
+		return nil, fmt.Errorf("circuit breaker open for %s", req.URL.Host)
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("error rewinding request body for retry: %v", err)
+				}
+				req.Body = body
+			}
+
+			delay := c.backoff(attempt)
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, err := c.inner.Do(req)
+		switch {
+		case err == nil && resp.StatusCode < 500:
+			c.recordSuccess(req.URL.Host)
+			return resp, nil
+		case err == nil:
+			lastErr = fmt.Errorf("server error: status %d", resp.StatusCode)
+			resp.Body.Close()
+		default:
+			lastErr = err
+		}
+
+		if attempt >= c.cfg.MaxRetries || req.Context().Err() != nil {
+			break
+		}
+		log.Warn("http request failed, retrying", "url", req.URL.String(), "attempt", attempt+1, "max_attempts", c.cfg.MaxRetries+1, "error", lastErr)
+	}
+
+	c.recordFailure(req.URL.Host)
+	return nil, fmt.Errorf("http request failed after %d attempts: %v", c.cfg.MaxRetries+1, lastErr)
+}
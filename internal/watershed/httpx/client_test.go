@@ -0,0 +1,199 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestClient(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	return NewClient(cfg)
+}
+
+func TestDoDoesNotRetryNon5xx(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(Config{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, BreakerThreshold: 10, BreakerCooldown: time.Hour})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 attempt for a non-5xx response, got %d", calls)
+	}
+}
+
+func TestDoRetries5xxThenSucceeds(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(Config{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, BreakerThreshold: 10, BreakerCooldown: time.Hour})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts before a 200, got %d", calls)
+	}
+}
+
+func TestDoAbortsOnContextCancellation(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// A long backoff: if Do slept through it instead of watching the
+	// context, this test would time out waiting for the result.
+	c := newTestClient(Config{MaxRetries: 5, BaseDelay: time.Hour, MaxDelay: time.Hour, BreakerThreshold: 100, BreakerCooldown: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Do(req)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error after the context was canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Do did not return promptly after context cancellation")
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 attempt before cancellation interrupted the backoff sleep, got %d", calls)
+	}
+}
+
+func TestBreakerOpensAfterThresholdAndResetsOnSuccess(t *testing.T) {
+	failing := true
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(Config{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, BreakerThreshold: 2, BreakerCooldown: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+		if _, err := c.Do(req); err == nil {
+			t.Fatalf("expected failure on attempt %d", i)
+		}
+	}
+	if !c.breakerOpen() {
+		t.Fatal("expected the breaker to be open after BreakerThreshold consecutive failures")
+	}
+
+	callsSoFar := calls
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected Do to fail fast while the breaker is open")
+	}
+	if calls != callsSoFar {
+		t.Fatalf("expected the open breaker to short-circuit the request, got %d new server calls", calls-callsSoFar)
+	}
+
+	// Simulate the cooldown elapsing and the upstream recovering.
+	c.mu.Lock()
+	c.openUntil = time.Now().Add(-time.Millisecond)
+	c.mu.Unlock()
+	failing = false
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error on recovery request: %v", err)
+	}
+	resp.Body.Close()
+
+	if c.breakerOpen() {
+		t.Fatal("expected the breaker to close after a successful request")
+	}
+}
+
+func TestOnBreakerChangeFiresOnOpenAndClose(t *testing.T) {
+	defer func() { OnBreakerChange = nil }()
+
+	var events []BreakerEvent
+	OnBreakerChange = func(ev BreakerEvent) { events = append(events, ev) }
+
+	failing := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(Config{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, BreakerThreshold: 1, BreakerCooldown: time.Hour})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	c.Do(req)
+
+	if len(events) != 1 || !events[0].Open {
+		t.Fatalf("expected a single open event, got %v", events)
+	}
+
+	c.mu.Lock()
+	c.openUntil = time.Now().Add(-time.Millisecond)
+	c.mu.Unlock()
+	failing = false
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error on recovery request: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(events) != 2 || events[1].Open {
+		t.Fatalf("expected a second, closing event, got %v", events)
+	}
+}
@@ -0,0 +1,42 @@
+// Package logging provides the process-wide structured logger used
+// across go_watershed in place of fmt.Printf/log.Printf.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var level = new(slog.LevelVar)
+
+// Logger is the process-wide structured logger.
+var Logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+
+func init() {
+	SetLevel(os.Getenv("LOG_LEVEL"))
+}
+
+// SetLevel parses "DEBUG"/"INFO"/"WARN"/"ERROR" (case-insensitive,
+// defaulting to INFO) and updates the logger's level in place, so a
+// change takes effect immediately without restarting the process.
+func SetLevel(s string) {
+	switch strings.ToUpper(s) {
+	case "DEBUG":
+		level.Set(slog.LevelDebug)
+	case "WARN", "WARNING":
+		level.Set(slog.LevelWarn)
+	case "ERROR":
+		level.Set(slog.LevelError)
+	default:
+		level.Set(slog.LevelInfo)
+	}
+}
+
+// ReloadFromEnv re-reads LOG_LEVEL and applies it. cmd/local calls it
+// on SIGHUP; its /log-level HTTP endpoint calls SetLevel directly
+// instead, and cmd/lambda wires up neither.
+func ReloadFromEnv() {
+	SetLevel(os.Getenv("LOG_LEVEL"))
+	Logger.Info("log level reloaded", "level", level.Level().String())
+}
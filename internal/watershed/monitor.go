@@ -2,309 +2,368 @@ package watershed
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"net/url"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/ses"
-)
 
-const (
-	baseURL      = "https://monitormywatershed.org/dataloader/ajax/"
-	samplingCode = "MSPL2S"
+	"github.com/Tricarico1/go_watershed/internal/watershed/anomaly"
+	"github.com/Tricarico1/go_watershed/internal/watershed/config"
+	"github.com/Tricarico1/go_watershed/internal/watershed/datasource"
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
+	"github.com/Tricarico1/go_watershed/internal/watershed/notify"
+	"github.com/Tricarico1/go_watershed/internal/watershed/sink"
 )
 
-// Move thresholds to be configurable
-type ThresholdConfig struct {
-	max float64
-	min float64
-}
-
-func getThresholdFromEnv(measurement string) ThresholdConfig {
-	maxKey := fmt.Sprintf("%s_MAX", measurement)
-	minKey := fmt.Sprintf("%s_MIN", measurement)
-
-	// Get values from environment with defaults
-	maxStr := os.Getenv(maxKey)
-	minStr := os.Getenv(minKey)
-
-	// Default values matching our original thresholds
-	defaults := map[string]ThresholdConfig{
-		"WATER_DEPTH":             {max: 1000, min: 0},
-		"TEMPERATURE":             {max: 26, min: -20},
-		"ELECTRICAL_CONDUCTIVITY": {max: 600, min: 0},
-		"TURBIDITY":               {max: 150, min: 0},
-		"BATTERY_VOLTAGE":         {max: 5, min: 0},
-		"PERCENT_FULL_SCALE":      {max: 101, min: 0},
-		"RELATIVE_HUMIDITY":       {max: 100, min: 0},
-	}
-
-	default_config := defaults[measurement]
-
-	// Parse environment variables if present, otherwise use defaults
-	max := default_config.max
-	if maxStr != "" {
-		if parsed, err := strconv.ParseFloat(maxStr, 64); err == nil {
-			max = parsed
-		}
-	}
-
-	min := default_config.min
-	if minStr != "" {
-		if parsed, err := strconv.ParseFloat(minStr, 64); err == nil {
-			min = parsed
-		}
-	}
+var log = logging.Logger
 
-	return ThresholdConfig{max: max, min: min}
-}
+// breakerAlertSuppression bounds how often a flapping circuit breaker
+// for the same host can re-alert, the same way a measurement's own
+// SuppressionWindow does for threshold and anomaly alerts.
+const breakerAlertSuppression = 15 * time.Minute
 
 type Monitor struct {
-	s3Client      *s3.S3
-	bucketName    string
-	lastEmailSent map[string]time.Time
+	s3Client   *s3.S3
+	bucketName string
+	cfg        *config.Config
+	sources    []datasource.DataSource
+	notifiers  []notify.Notifier
+	sinks      []sink.Sink
+	// anomalies holds its rolling sample window purely in memory, so
+	// rate/z-score/stuck-sensor rules only ever see history from
+	// invocations that share this Monitor. On Lambda that means the
+	// caller must reuse one Monitor across warm invocations (see the
+	// package-level monitor in cmd/lambda) rather than building a new
+	// one, with an empty window, per request.
+	anomalies *anomaly.Evaluator
+	// lastSent tracks, per notifier name and measurement, when an
+	// alert was last delivered, so suppression can be computed
+	// independently per channel.
+	lastSent map[string]map[string]time.Time
+	// runCtx is the context of the RunOnce call currently (or most
+	// recently) in flight. handleBreakerChange is invoked directly by
+	// httpx.Client from deep inside a data source or notifier call, so
+	// it has no ctx parameter of its own to thread through; it reuses
+	// this one so its alert send still respects the run's deadline.
+	runCtx context.Context
 }
 
 func NewMonitor() *Monitor {
 	sess := session.Must(session.NewSession())
-	return &Monitor{
-		s3Client:      s3.New(sess),
-		bucketName:    "watershed-monitor-state",
-		lastEmailSent: make(map[string]time.Time),
-	}
-}
 
-type RequestData struct {
-	Method              string `json:"method"`
-	SamplingFeatureCode string `json:"sampling_feature_code,omitempty"`
-	ResultID            string `json:"resultid,omitempty"`
-	StartDate           string `json:"start_date,omitempty"`
-	EndDate             string `json:"end_date,omitempty"`
-}
+	cfg, err := config.FromEnv()
+	if err != nil {
+		log.Warn("could not load measurement config, falling back to defaults", "error", err)
+		cfg = config.Default()
+	}
 
-type TimeSeriesData struct {
-	ValueID             map[string]int64   `json:"valueid"`
-	DataValue           map[string]float64 `json:"datavalue"`
-	ValueDateTime       map[string]int64   `json:"valuedatetime"`
-	ValueDateTimeOffset map[string]int     `json:"valuedatetimeutcoffset"`
-}
+	m := &Monitor{
+		s3Client:   s3.New(sess),
+		bucketName: "watershed-monitor-state",
+		cfg:        cfg,
+		sources:    datasource.FromEnv(),
+		notifiers:  notify.FromEnv(),
+		sinks:      sink.FromEnv(),
+		anomalies:  anomaly.NewEvaluatorFromEnv(),
+		lastSent:   make(map[string]map[string]time.Time),
+		runCtx:     context.Background(),
+	}
 
-func (m *Monitor) sendEmailSES(subject, body string) error {
-	// Add debug logging
-	fmt.Printf("Attempting to send email:\nFrom: %s\nTo: %s\nSubject: %s\n",
-		os.Getenv("SES_FROM_ADDRESS"),
-		os.Getenv("EMAIL_RECIPIENT"),
-		subject)
+	// Every httpx.Client shares this one process-wide hook, so a
+	// breaker trip in any data source or notifier surfaces as an alert
+	// here rather than only in logs.
+	httpx.OnBreakerChange = m.handleBreakerChange
 
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(os.Getenv("AWS_REGION")),
-	})
-	if err != nil {
-		return fmt.Errorf("session error: %v", err)
-	}
-
-	svc := ses.New(sess)
-	input := &ses.SendEmailInput{
-		Destination: &ses.Destination{
-			ToAddresses: []*string{
-				aws.String(os.Getenv("EMAIL_RECIPIENT")),
-			},
-		},
-		Message: &ses.Message{
-			Body: &ses.Body{
-				Text: &ses.Content{
-					Data: aws.String(body),
-				},
-			},
-			Subject: &ses.Content{
-				Data: aws.String(subject),
-			},
-		},
-		Source: aws.String(os.Getenv("SES_FROM_ADDRESS")),
-	}
-
-	result, err := svc.SendEmail(input)
-	if err != nil {
-		return fmt.Errorf("send error: %v", err)
-	}
-	fmt.Printf("Email sent! Message ID: %s\n", *result.MessageId)
-	return nil
+	return m
 }
 
-func (m *Monitor) getLastEmailTime(name string) (time.Time, bool) {
-	lastSent, exists := m.lastEmailSent[name]
-	return lastSent, exists
-}
+// handleBreakerChange turns a circuit breaker transition from any
+// httpx.Client into an alert. Breaker events aren't tied to a single
+// measurement, so - unlike checkAndNotify/notifyAnomaly - this fans
+// out to every configured notifier rather than routing through
+// notifiersFor, and suppresses repeats per host rather than per
+// measurement.
+func (m *Monitor) handleBreakerChange(ev httpx.BreakerEvent) {
+	suppressKey := "breaker:" + ev.Host
 
-func (m *Monitor) updateLastEmailTime(name string, t time.Time) error {
-	m.lastEmailSent[name] = t
-	return nil
-}
+	subject := fmt.Sprintf("Circuit breaker opened for %s", ev.Host)
+	body := fmt.Sprintf("Repeated failures talking to %s; requests are being failed fast until %s.", ev.Host, ev.OpenUntil.Format(time.RFC3339))
+	if !ev.Open {
+		subject = fmt.Sprintf("Circuit breaker closed for %s", ev.Host)
+		body = fmt.Sprintf("Requests to %s are succeeding again.", ev.Host)
+	}
 
-func (m *Monitor) checkAndNotify(name string, value float64, timestamp time.Time) {
-	envName := strings.ReplaceAll(strings.ToUpper(name), " ", "_")
-	threshold := getThresholdFromEnv(envName)
+	log.Warn("circuit breaker state changed", "host", ev.Host, "open", ev.Open)
 
-	if value >= threshold.max || value < threshold.min {
-		fmt.Printf("\nALERT: %s value %.2f is outside acceptable range (%.2f to %.2f)\n",
-			name, value, threshold.min, threshold.max)
+	alert := notify.Alert{
+		Measurement: ev.Host,
+		Timestamp:   time.Now(),
+		Subject:     subject,
+		Body:        body,
+	}
 
-		// Check for last email time
-		if lastSent, exists := m.getLastEmailTime(name); exists {
-			timeSince := time.Since(lastSent)
-			if timeSince < 12*time.Hour {
-				hoursLeft := 12 - timeSince.Hours()
-				fmt.Printf("Notice: Email alert suppressed - previous alert was sent %.1f hours ago (waiting %.1f more hours)\n",
-					timeSince.Hours(), hoursLeft)
-				return
+	for _, n := range m.notifiers {
+		if lastSent, exists := m.getLastSent(n.Name(), suppressKey); exists {
+			if timeSince := time.Since(lastSent); timeSince < breakerAlertSuppression {
+				log.Info("breaker alert suppressed", "channel", n.Name(), "host", ev.Host, "time_since_last", timeSince)
+				continue
 			}
 		}
 
-		// Only attempt email if configured
-		if os.Getenv("EMAIL_RECIPIENT") == "" {
-			fmt.Println("Notice: Email alert suppressed - no email recipient configured")
-			return
+		if err := n.Send(m.runCtx, alert); err != nil {
+			log.Error("error sending breaker alert", "channel", n.Name(), "host", ev.Host, "error", err)
+			continue
 		}
 
-		// Format timestamp in EST
-		estTime := timestamp.Add(-5 * time.Hour)
-		timeStr := estTime.Format("2006-01-02 15:04:05 EST")
+		m.updateLastSent(n.Name(), suppressKey, time.Now())
+		log.Info("breaker alert sent", "channel", n.Name(), "host", ev.Host, "open", ev.Open)
+	}
+}
 
-		subject := fmt.Sprintf("%s Alert", name)
-		body := fmt.Sprintf("%s has reached %.2f at %s\n(Acceptable range: %.2f to %.2f)",
-			name, value, timeStr, threshold.min, threshold.max)
+// notifiersFor resolves which notifiers an alert for mc should go to.
+// A measurement with no routes fans out to every configured notifier,
+// matching the tool's original behavior; one with routes only reaches
+// notifiers named by a rule whose labels are all satisfied by mc's
+// labels.
+func (m *Monitor) notifiersFor(mc config.Measurement) []notify.Notifier {
+	if len(mc.Routes) == 0 {
+		return m.notifiers
+	}
 
-		if err := m.sendEmailSES(subject, body); err != nil {
-			fmt.Printf("Error sending email for %s: %v\n", name, err)
-			return // Don't update last email time if sending failed
+	wanted := make(map[string]bool)
+	for _, route := range mc.Routes {
+		if !labelsMatch(mc.Labels, route.Labels) {
+			continue
+		}
+		for _, name := range route.Notifiers {
+			wanted[name] = true
 		}
+	}
 
-		// Update with new time
-		if err := m.updateLastEmailTime(name, time.Now()); err != nil {
-			fmt.Printf("Warning: Failed to update last email time: %v\n", err)
-			return
+	var matched []notify.Notifier
+	for _, n := range m.notifiers {
+		if wanted[n.Name()] {
+			matched = append(matched, n)
 		}
-		fmt.Printf("Alert email sent for %s (value: %.2f)\n", name, value)
 	}
+	return matched
 }
 
-func (m *Monitor) makePostRequest(urlStr string, formValues map[string]string) ([]byte, error) {
-	formData := make(url.Values)
-	for key, value := range formValues {
-		formData.Set(key, value)
+// labelsMatch reports whether have contains every key/value pair in
+// want.
+func labelsMatch(have, want map[string]string) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
 	}
+	return true
+}
 
-	req, err := http.NewRequest("POST", urlStr, bytes.NewBufferString(formData.Encode()))
-	if err != nil {
-		return nil, err
+func (m *Monitor) getLastSent(channel, measurement string) (time.Time, bool) {
+	byMeasurement, exists := m.lastSent[channel]
+	if !exists {
+		return time.Time{}, false
 	}
+	t, exists := byMeasurement[measurement]
+	return t, exists
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Origin", "https://monitormywatershed.org")
-	req.Header.Set("Referer", "https://monitormywatershed.org/tsv/")
+func (m *Monitor) updateLastSent(channel, measurement string, t time.Time) {
+	if m.lastSent[channel] == nil {
+		m.lastSent[channel] = make(map[string]time.Time)
+	}
+	m.lastSent[channel][measurement] = t
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// checkAndNotify evaluates name/value against its configured
+// measurement and, if out of range, fans the alert out to whichever
+// notifiers its routing rules select. Suppression is tracked
+// independently per notifier so one slow or misconfigured channel
+// can't hold back the others. Hysteresis only widens the acceptable
+// range while the most recent alert is still within the measurement's
+// suppression window, so a single past excursion doesn't silently
+// mask a real one indefinitely. ctx bounds the outbound alert sends.
+func (m *Monitor) checkAndNotify(ctx context.Context, name string, value float64, timestamp time.Time) {
+	mc, ok := m.cfg.Lookup(name)
+	if !ok {
+		log.Warn("no config for measurement, skipping", "measurement", name)
+		return
 	}
-	defer resp.Body.Close()
 
-	return ioutil.ReadAll(resp.Body)
-}
+	m.anomalies.Observe(mc.Name, anomaly.Sample{Value: value, Timestamp: timestamp})
+	for _, finding := range m.anomalies.Evaluate(mc.Name, mc.Rules) {
+		m.notifyAnomaly(ctx, mc, finding, timestamp)
+	}
 
-func (m *Monitor) fetchResultID() (map[string]string, error) {
-	data := map[string]string{
-		"request_data": fmt.Sprintf(`{"method":"get_sampling_feature_metadata","sampling_feature_code":"%s"}`, samplingCode),
+	min, max := mc.Min, mc.Max
+	if lastSent, alerted := m.getLastSent("*", name); alerted && time.Since(lastSent) < mc.SuppressionWindow() {
+		min -= mc.Hysteresis
+		max += mc.Hysteresis
 	}
-	response, err := m.makePostRequest(baseURL, data)
-	if err != nil {
-		return nil, err
+
+	inRange := value < max && value >= min
+	log.Debug("threshold evaluation", "measurement", mc.Label(), "value", value, "min", min, "max", max, "in_range", inRange)
+	if inRange {
+		return
 	}
 
-	var jsonStr string
-	if err := json.Unmarshal(response, &jsonStr); err != nil {
-		return nil, fmt.Errorf("first unmarshal error: %v", err)
+	log.Warn("threshold violation", "measurement", mc.Label(), "value", value, "min", mc.Min, "max", mc.Max)
+
+	notifiers := m.notifiersFor(mc)
+	if len(notifiers) == 0 {
+		log.Warn("alert not delivered - no notifiers matched routing", "measurement", mc.Label())
+		return
 	}
 
-	var results []map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &results); err != nil {
-		return nil, fmt.Errorf("second unmarshal error: %v", err)
+	estTime := timestamp.Add(-5 * time.Hour)
+	timeStr := estTime.Format("2006-01-02 15:04:05 EST")
+
+	alert := notify.Alert{
+		Measurement: mc.Label(),
+		Value:       value,
+		Min:         mc.Min,
+		Max:         mc.Max,
+		Timestamp:   timestamp,
+		Subject:     fmt.Sprintf("%s Alert", mc.Label()),
+		Body: fmt.Sprintf("%s has reached %.2f at %s\n(Acceptable range: %.2f to %.2f)",
+			mc.Label(), value, timeStr, mc.Min, mc.Max),
 	}
 
-	measurements := make(map[string]string)
-	for _, result := range results {
-		name, ok1 := result["variablenamecv"].(string)
-		resultID, ok2 := result["resultid"].(float64)
-		if !ok1 || !ok2 {
+	suppressFor := mc.SuppressionWindow()
+	for _, n := range notifiers {
+		if lastSent, exists := m.getLastSent(n.Name(), name); exists {
+			timeSince := time.Since(lastSent)
+			if timeSince < suppressFor {
+				log.Info("alert suppressed", "channel", n.Name(), "measurement", mc.Label(), "time_since_last", timeSince, "suppress_for", suppressFor)
+				continue
+			}
+		}
+
+		if err := n.Send(ctx, alert); err != nil {
+			log.Error("error sending alert", "channel", n.Name(), "measurement", mc.Label(), "value", value, "error", err)
 			continue
 		}
-		measurements[name] = fmt.Sprintf("%d", int(resultID))
-	}
 
-	return measurements, nil
+		m.updateLastSent(n.Name(), name, time.Now())
+		m.updateLastSent("*", name, time.Now())
+		log.Info("alert sent", "channel", n.Name(), "measurement", mc.Label(), "value", value)
+	}
 }
 
-func (m *Monitor) fetchTimeSeriesData(name, resultID string) error {
-	now := time.Now()
-	startDate := now.Add(-5 * time.Minute).Format(time.RFC3339)
-	endDate := now.Format(time.RFC3339)
+// notifyAnomaly dispatches a rolling-window rule finding the same way
+// checkAndNotify dispatches a threshold violation: routed through
+// notifiersFor and suppressed independently per notifier, keyed by
+// both measurement and rule kind so e.g. a Rate finding doesn't
+// suppress a later Stuck finding on the same measurement. ctx bounds
+// the outbound alert sends.
+func (m *Monitor) notifyAnomaly(ctx context.Context, mc config.Measurement, finding anomaly.Finding, timestamp time.Time) {
+	log.Warn("anomaly detected", "measurement", mc.Label(), "rule", finding.Kind, "value", finding.Value, "message", finding.Message)
 
-	data := map[string]string{
-		"request_data": fmt.Sprintf(`{"method":"get_result_timeseries","resultid":"%s","start_date":"%s","end_date":"%s"}`, resultID, startDate, endDate),
+	notifiers := m.notifiersFor(mc)
+	if len(notifiers) == 0 {
+		log.Warn("anomaly alert not delivered - no notifiers matched routing", "measurement", mc.Label(), "rule", finding.Kind)
+		return
 	}
-	response, err := m.makePostRequest(baseURL, data)
-	if err != nil {
-		return fmt.Errorf("error fetching %s: %v", name, err)
+
+	suppressKey := fmt.Sprintf("%s:%s", mc.Name, finding.Kind)
+	estTime := timestamp.Add(-5 * time.Hour)
+	timeStr := estTime.Format("2006-01-02 15:04:05 EST")
+
+	alert := notify.Alert{
+		Measurement: mc.Label(),
+		Value:       finding.Value,
+		Timestamp:   timestamp,
+		Subject:     fmt.Sprintf("%s Anomaly (%s)", mc.Label(), finding.Kind),
+		Body:        fmt.Sprintf("%s at %s: %s", mc.Label(), timeStr, finding.Message),
 	}
 
-	var jsonStr string
-	if err := json.Unmarshal(response, &jsonStr); err != nil {
-		return fmt.Errorf("error parsing response for %s: %v", name, err)
+	suppressFor := mc.SuppressionWindow()
+	for _, n := range notifiers {
+		if lastSent, exists := m.getLastSent(n.Name(), suppressKey); exists {
+			timeSince := time.Since(lastSent)
+			if timeSince < suppressFor {
+				log.Info("anomaly alert suppressed", "channel", n.Name(), "measurement", mc.Label(), "rule", finding.Kind, "time_since_last", timeSince, "suppress_for", suppressFor)
+				continue
+			}
+		}
+
+		if err := n.Send(ctx, alert); err != nil {
+			log.Error("error sending anomaly alert", "channel", n.Name(), "measurement", mc.Label(), "rule", finding.Kind, "error", err)
+			continue
+		}
+
+		m.updateLastSent(n.Name(), suppressKey, time.Now())
+		log.Info("anomaly alert sent", "channel", n.Name(), "measurement", mc.Label(), "rule", finding.Kind, "value", finding.Value)
 	}
+}
 
-	var timeSeriesData TimeSeriesData
-	if err := json.Unmarshal([]byte(jsonStr), &timeSeriesData); err != nil {
-		return fmt.Errorf("error parsing data for %s: %v", name, err)
+// fetchSeriesForMeasurement pulls the last few minutes of samples for
+// a single measurement from src, persists them, and evaluates each
+// one.
+func (m *Monitor) fetchSeriesForMeasurement(ctx context.Context, src datasource.DataSource, name, id string) error {
+	since := time.Now().Add(-5 * time.Minute)
+	samples, err := src.FetchSeries(ctx, id, since)
+	if err != nil {
+		return fmt.Errorf("error fetching %s from %s: %v", name, src.Name(), err)
 	}
 
-	fmt.Printf("\n=== %s ===\n", name)
-	for key := range timeSeriesData.DataValue {
-		utcTime := time.Unix(timeSeriesData.ValueDateTime[key]/1000, 0)
-		estTime := utcTime.Add(-5 * time.Hour)
-		value := timeSeriesData.DataValue[key]
+	log.Debug("fetched series", "measurement", name, "source", src.Name(), "samples", len(samples))
+	m.persistSamples(ctx, name, samples)
 
-		fmt.Printf("Time (EST): %s\n", estTime.Format("2006-01-02 15:04:05"))
-		fmt.Printf("Value: %.2f\n", value)
-		fmt.Println("-------------------")
+	for _, sample := range samples {
+		estTime := sample.Timestamp.Add(-5 * time.Hour)
+		log.Debug("sample", "measurement", name, "source", src.Name(), "value", sample.Value, "time_est", estTime.Format("2006-01-02 15:04:05"))
 
-		m.checkAndNotify(name, value, utcTime)
+		m.checkAndNotify(ctx, name, sample.Value, sample.Timestamp)
 	}
 
 	return nil
 }
 
+// persistSamples writes every fetched sample to each configured sink,
+// regardless of whether it breaches a threshold, so historical values
+// survive for later read-back and debugging.
+func (m *Monitor) persistSamples(ctx context.Context, measurement string, samples []datasource.Sample) {
+	for _, sk := range m.sinks {
+		for _, sample := range samples {
+			if err := sk.Write(ctx, measurement, sample); err != nil {
+				log.Error("error persisting sample", "sink", sk.Name(), "measurement", measurement, "error", err)
+			}
+		}
+	}
+}
+
+// QuerySeries reads back samples for measurement between from and to
+// from the first configured sink that supports querying. ok is false
+// when no sink does, in which case err is always nil.
+func (m *Monitor) QuerySeries(ctx context.Context, measurement string, from, to time.Time) (samples []datasource.Sample, ok bool, err error) {
+	qs, ok := sink.Queryable(m.sinks)
+	if !ok {
+		return nil, false, nil
+	}
+	samples, err = qs.Query(ctx, measurement, from, to)
+	return samples, true, err
+}
+
 func (m *Monitor) loadState() error {
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(m.bucketName),
-		Key:    aws.String("last_email_times.json"),
+		Key:    aws.String("last_notified_times.json"),
 	}
 
 	result, err := m.s3Client.GetObject(input)
 	if err != nil {
 		// If the file doesn't exist, that's okay - we'll start fresh
 		if strings.Contains(err.Error(), "NoSuchKey") {
-			fmt.Println("No existing state found, starting fresh")
-			m.lastEmailSent = make(map[string]time.Time)
+			log.Info("no existing state found, starting fresh")
+			m.lastSent = make(map[string]map[string]time.Time)
 			return nil
 		}
 		return fmt.Errorf("error loading state from S3: %v", err)
@@ -312,36 +371,36 @@ func (m *Monitor) loadState() error {
 	defer result.Body.Close()
 
 	// Reset the map before loading
-	m.lastEmailSent = make(map[string]time.Time)
+	m.lastSent = make(map[string]map[string]time.Time)
 
 	decoder := json.NewDecoder(result.Body)
-	if err := decoder.Decode(&m.lastEmailSent); err != nil {
+	if err := decoder.Decode(&m.lastSent); err != nil {
 		return fmt.Errorf("error decoding state: %v", err)
 	}
 
-	// Print current state for debugging
-	fmt.Println("Loaded state from S3:")
-	for k, v := range m.lastEmailSent {
-		fmt.Printf("- %s: last email sent at %v\n", k, v)
+	for channel, byMeasurement := range m.lastSent {
+		for measurement, t := range byMeasurement {
+			log.Debug("loaded state entry", "channel", channel, "measurement", measurement, "last_sent", t)
+		}
 	}
 
 	return nil
 }
 
 func (m *Monitor) saveState() error {
-	if len(m.lastEmailSent) == 0 {
-		fmt.Println("No state to save")
+	if len(m.lastSent) == 0 {
+		log.Debug("no state to save")
 		return nil
 	}
 
-	data, err := json.Marshal(m.lastEmailSent)
+	data, err := json.Marshal(m.lastSent)
 	if err != nil {
 		return fmt.Errorf("error marshaling state: %v", err)
 	}
 
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(m.bucketName),
-		Key:    aws.String("last_email_times.json"),
+		Key:    aws.String("last_notified_times.json"),
 		Body:   bytes.NewReader(data),
 	}
 
@@ -350,55 +409,67 @@ func (m *Monitor) saveState() error {
 		return fmt.Errorf("error saving state to S3: %v", err)
 	}
 
-	fmt.Println("Successfully saved state to S3")
+	log.Info("state saved to S3")
 	return nil
 }
 
-func (m *Monitor) RunOnce() error {
-	fmt.Println("Starting monitor run...")
+// RunOnce fetches and evaluates every configured measurement once.
+// ctx bounds the whole run, including every upstream HTTP call made
+// through it, so a caller with a deadline (Lambda's invocation
+// context, or a timeout set by the continuous-mode loop) can't have
+// an outage hang it past that deadline.
+func (m *Monitor) RunOnce(ctx context.Context) error {
+	log.Info("starting monitor run")
+	m.runCtx = ctx
 
 	// Load state at start
 	if err := m.loadState(); err != nil {
-		fmt.Printf("Warning: Could not load state: %v\n", err)
+		log.Warn("could not load state", "error", err)
 	}
 
-	fmt.Println("Fetching measurement IDs...")
-	measurements, err := m.fetchResultID()
-	if err != nil {
-		return fmt.Errorf("error fetching measurements: %v", err)
-	}
+	successCount := 0
+	attemptCount := 0
+	for _, src := range m.sources {
+		if err := ctx.Err(); err != nil {
+			log.Warn("aborting monitor run - context done", "error", err)
+			break
+		}
 
-	desiredMeasurements := []string{
-		"Water depth",
-		"Temperature",
-		"Electrical conductivity",
-		"Turbidity",
-		"Battery voltage",
-		"Percent full scale",
-		"Relative humidity",
-	}
+		log.Debug("fetching measurement list", "source", src.Name())
+		measurements, err := src.ListMeasurements(ctx)
+		if err != nil {
+			log.Error("error listing measurements", "source", src.Name(), "error", err)
+			continue
+		}
 
-	fmt.Printf("Found %d measurements, processing...\n", len(measurements))
+		byName := make(map[string]string, len(measurements))
+		for _, measurement := range measurements {
+			byName[measurement.Name] = measurement.ID
+		}
 
-	successCount := 0
-	for _, name := range desiredMeasurements {
-		if resultID, ok := measurements[name]; ok {
-			fmt.Printf("Processing %s...\n", name)
-			if err := m.fetchTimeSeriesData(name, resultID); err != nil {
-				fmt.Printf("Error processing %s: %v\n", name, err)
+		log.Debug("found measurements", "source", src.Name(), "count", len(measurements))
+
+		for _, mc := range m.cfg.Measurements {
+			id, ok := byName[mc.Name]
+			if !ok {
+				continue
+			}
+			attemptCount++
+			if err := m.fetchSeriesForMeasurement(ctx, src, mc.Name, id); err != nil {
+				log.Error("error processing measurement", "measurement", mc.Name, "source", src.Name(), "error", err)
 				continue
 			}
 			successCount++
 		}
 	}
 
-	fmt.Printf("Successfully processed %d/%d measurements\n", successCount, len(desiredMeasurements))
+	log.Info("finished processing measurements", "succeeded", successCount, "attempted", attemptCount)
 
 	// Save state after processing
 	if err := m.saveState(); err != nil {
-		fmt.Printf("Warning: Could not save state: %v\n", err)
+		log.Warn("could not save state", "error", err)
 	}
 
-	fmt.Println("Monitor run completed")
+	log.Info("monitor run completed")
 	return nil
 }
@@ -0,0 +1,36 @@
+package notify
+
+// FromEnv builds the set of Notifiers configured via environment
+// variables, skipping any channel whose variables are unset. Each
+// Notifier is logged as it's wired up so operators can see which
+// channels are active.
+func FromEnv() []Notifier {
+	var notifiers []Notifier
+
+	if n, ok := NewSESFromEnv(); ok {
+		notifiers = append(notifiers, n)
+		log.Info("notifier configured", "channel", "ses")
+	}
+	if n, ok := NewSMTPFromEnv(); ok {
+		notifiers = append(notifiers, n)
+		log.Info("notifier configured", "channel", "smtp")
+	}
+	if n, ok := NewSlackFromEnv(); ok {
+		notifiers = append(notifiers, n)
+		log.Info("notifier configured", "channel", "slack")
+	}
+	if n, ok := NewPagerDutyFromEnv(); ok {
+		notifiers = append(notifiers, n)
+		log.Info("notifier configured", "channel", "pagerduty")
+	}
+	if n, ok := NewWebhookFromEnv(); ok {
+		notifiers = append(notifiers, n)
+		log.Info("notifier configured", "channel", "webhook")
+	}
+
+	if len(notifiers) == 0 {
+		log.Warn("no notification channels configured - running in monitoring-only mode")
+	}
+
+	return notifiers
+}
@@ -0,0 +1,30 @@
+// Package notify defines the notification-channel abstraction used to
+// fan alerts out to email, chat, and paging backends.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Alert describes a single alert to deliver through a channel: a
+// threshold violation, an anomaly finding, or a circuit breaker state
+// change. Min and Max are only set for threshold violations.
+type Alert struct {
+	Measurement string
+	Value       float64
+	Min         float64
+	Max         float64
+	Timestamp   time.Time
+	Subject     string
+	Body        string
+}
+
+// Notifier delivers an Alert through a single channel (email, chat,
+// paging, webhook, ...).
+type Notifier interface {
+	// Name identifies the channel, e.g. "ses" or "slack". Used as the
+	// key for per-channel suppression state.
+	Name() string
+	Send(ctx context.Context, alert Alert) error
+}
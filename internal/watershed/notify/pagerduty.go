@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifier triggers an incident via the PagerDuty Events API
+// v2.
+type PagerDutyNotifier struct {
+	routingKey string
+	client     *httpx.Client
+}
+
+// NewPagerDutyFromEnv builds a PagerDutyNotifier from
+// PAGERDUTY_ROUTING_KEY. It returns ok=false when the variable is
+// unset.
+func NewPagerDutyFromEnv() (*PagerDutyNotifier, bool) {
+	routingKey := os.Getenv("PAGERDUTY_ROUTING_KEY")
+	if routingKey == "" {
+		return nil, false
+	}
+	return &PagerDutyNotifier{routingKey: routingKey, client: httpx.NewClientFromEnv()}, true
+}
+
+type pagerDutyPayload struct {
+	RoutingKey  string             `json:"routing_key"`
+	EventAction string             `json:"event_action"`
+	Payload     pagerDutyEventBody `json:"payload"`
+}
+
+type pagerDutyEventBody struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+func (n *PagerDutyNotifier) Name() string { return "pagerduty" }
+
+func (n *PagerDutyNotifier) Send(ctx context.Context, alert Alert) error {
+	payload := pagerDutyPayload{
+		RoutingKey:  n.routingKey,
+		EventAction: "trigger",
+		Payload: pagerDutyEventBody{
+			Summary:   alert.Subject + ": " + alert.Body,
+			Source:    "go_watershed",
+			Severity:  "critical",
+			Timestamp: alert.Timestamp.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling pagerduty payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", pagerDutyEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to pagerduty: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pagerduty events api returned status %d", resp.StatusCode)
+	}
+	return nil
+}
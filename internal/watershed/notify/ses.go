@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
+)
+
+var log = logging.Logger
+
+// SESNotifier sends alerts as email via AWS SES.
+type SESNotifier struct {
+	client    *ses.SES
+	from      string
+	recipient string
+}
+
+// NewSESFromEnv builds an SESNotifier from SES_FROM_ADDRESS and
+// EMAIL_RECIPIENT. It returns ok=false when either is unset so callers
+// can skip wiring up the channel.
+func NewSESFromEnv() (*SESNotifier, bool) {
+	from := os.Getenv("SES_FROM_ADDRESS")
+	recipient := os.Getenv("EMAIL_RECIPIENT")
+	if from == "" || recipient == "" {
+		return nil, false
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	})
+	if err != nil {
+		log.Warn("could not create SES session", "error", err)
+		return nil, false
+	}
+
+	return &SESNotifier{
+		client:    ses.New(sess),
+		from:      from,
+		recipient: recipient,
+	}, true
+}
+
+func (n *SESNotifier) Name() string { return "ses" }
+
+func (n *SESNotifier) Send(ctx context.Context, alert Alert) error {
+	log.Debug("sending SES email", "from", n.from, "to", n.recipient, "subject", alert.Subject, "measurement", alert.Measurement, "value", alert.Value)
+
+	input := &ses.SendEmailInput{
+		Destination: &ses.Destination{
+			ToAddresses: []*string{aws.String(n.recipient)},
+		},
+		Message: &ses.Message{
+			Body: &ses.Body{
+				Text: &ses.Content{Data: aws.String(alert.Body)},
+			},
+			Subject: &ses.Content{Data: aws.String(alert.Subject)},
+		},
+		Source: aws.String(n.from),
+	}
+
+	result, err := n.client.SendEmailWithContext(ctx, input)
+	if err != nil {
+		return fmt.Errorf("send error: %v", err)
+	}
+	log.Debug("SES email sent", "message_id", *result.MessageId, "measurement", alert.Measurement)
+	return nil
+}
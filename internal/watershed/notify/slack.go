@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+// SlackNotifier posts alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	webhookURL string
+	client     *httpx.Client
+}
+
+// NewSlackFromEnv builds a SlackNotifier from SLACK_WEBHOOK_URL. It
+// returns ok=false when the variable is unset.
+func NewSlackFromEnv() (*SlackNotifier, bool) {
+	webhookURL := os.Getenv("SLACK_WEBHOOK_URL")
+	if webhookURL == "" {
+		return nil, false
+	}
+	return &SlackNotifier{webhookURL: webhookURL, client: httpx.NewClientFromEnv()}, true
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Send(ctx context.Context, alert Alert) error {
+	payload := slackPayload{Text: fmt.Sprintf("*%s*\n%s", alert.Subject, alert.Body)}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling slack payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to slack: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
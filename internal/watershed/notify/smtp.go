@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+)
+
+// SMTPNotifier sends alerts as email through a generic SMTP server,
+// replacing the old Gmail-only path in go_watershed.go.
+type SMTPNotifier struct {
+	host      string
+	port      string
+	user      string
+	password  string
+	recipient string
+}
+
+// NewSMTPFromEnv builds an SMTPNotifier from SMTP_HOST, SMTP_PORT,
+// SMTP_USER, SMTP_PASSWORD and EMAIL_RECIPIENT. SMTP_PORT defaults to
+// 587. It returns ok=false when host, user, password or recipient is
+// unset.
+func NewSMTPFromEnv() (*SMTPNotifier, bool) {
+	host := os.Getenv("SMTP_HOST")
+	user := os.Getenv("SMTP_USER")
+	password := os.Getenv("SMTP_PASSWORD")
+	recipient := os.Getenv("EMAIL_RECIPIENT")
+	if host == "" || user == "" || password == "" || recipient == "" {
+		return nil, false
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	return &SMTPNotifier{
+		host:      host,
+		port:      port,
+		user:      user,
+		password:  password,
+		recipient: recipient,
+	}, true
+}
+
+func (n *SMTPNotifier) Name() string { return "smtp" }
+
+func (n *SMTPNotifier) Send(ctx context.Context, alert Alert) error {
+	auth := smtp.PlainAuth("", n.user, n.password, n.host)
+
+	msg := fmt.Sprintf("From: %s\nTo: %s\nSubject: %s\n\n%s",
+		n.user, n.recipient, alert.Subject, alert.Body)
+
+	return smtp.SendMail(
+		n.host+":"+n.port,
+		auth,
+		n.user,
+		[]string{n.recipient},
+		[]byte(msg),
+	)
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+// WebhookNotifier POSTs a structured JSON payload to an arbitrary
+// HTTP endpoint.
+type WebhookNotifier struct {
+	url    string
+	client *httpx.Client
+}
+
+// NewWebhookFromEnv builds a WebhookNotifier from WEBHOOK_URL. It
+// returns ok=false when the variable is unset.
+func NewWebhookFromEnv() (*WebhookNotifier, bool) {
+	url := os.Getenv("WEBHOOK_URL")
+	if url == "" {
+		return nil, false
+	}
+	return &WebhookNotifier{url: url, client: httpx.NewClientFromEnv()}, true
+}
+
+type webhookPayload struct {
+	Measurement string  `json:"measurement"`
+	Value       float64 `json:"value"`
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+	Timestamp   string  `json:"timestamp"`
+	Subject     string  `json:"subject"`
+	Body        string  `json:"body"`
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Send(ctx context.Context, alert Alert) error {
+	payload := webhookPayload{
+		Measurement: alert.Measurement,
+		Value:       alert.Value,
+		Min:         alert.Min,
+		Max:         alert.Max,
+		Timestamp:   alert.Timestamp.UTC().Format("2006-01-02T15:04:05Z"),
+		Subject:     alert.Subject,
+		Body:        alert.Body,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package sink
+
+// FromEnv builds the set of Sinks configured via environment
+// variables, skipping any sink whose variables are unset. Each sink
+// is logged as it's wired up so operators can see where samples are
+// being persisted.
+func FromEnv() []Sink {
+	var sinks []Sink
+
+	if s, ok := NewS3JSONLFromEnv(); ok {
+		sinks = append(sinks, s)
+		log.Info("sink configured", "sink", "s3-jsonl")
+	}
+	if s, ok := NewParquetFromEnv(); ok {
+		sinks = append(sinks, s)
+		log.Info("sink configured", "sink", "parquet")
+	}
+	if s, ok := NewInfluxDBWriterFromEnv(); ok {
+		sinks = append(sinks, s)
+		log.Info("sink configured", "sink", "influxdb-writer")
+	}
+
+	if len(sinks) == 0 {
+		log.Debug("no sample sinks configured - raw samples won't be persisted")
+	}
+
+	return sinks
+}
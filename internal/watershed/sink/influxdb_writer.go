@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/datasource"
+	"github.com/Tricarico1/go_watershed/internal/watershed/httpx"
+)
+
+// InfluxDBWriter writes samples to InfluxDB using the line protocol,
+// for deployments that want raw samples recorded alongside whatever
+// Telegraf already writes there. It's write-only: read historical
+// values back through InfluxDB itself, not this tool's debug API.
+type InfluxDBWriter struct {
+	addr     string
+	database string
+	token    string
+	client   *httpx.Client
+}
+
+// NewInfluxDBWriterFromEnv builds an InfluxDBWriter from
+// SINK_INFLUXDB_ADDR, SINK_INFLUXDB_DATABASE and
+// SINK_INFLUXDB_TOKEN. It returns ok=false when addr or database is
+// unset.
+func NewInfluxDBWriterFromEnv() (*InfluxDBWriter, bool) {
+	addr := os.Getenv("SINK_INFLUXDB_ADDR")
+	database := os.Getenv("SINK_INFLUXDB_DATABASE")
+	if addr == "" || database == "" {
+		return nil, false
+	}
+	return &InfluxDBWriter{
+		addr:     strings.TrimRight(addr, "/"),
+		database: database,
+		token:    os.Getenv("SINK_INFLUXDB_TOKEN"),
+		client:   httpx.NewClientFromEnv(),
+	}, true
+}
+
+func (w *InfluxDBWriter) Name() string { return "influxdb-writer" }
+
+func (w *InfluxDBWriter) Write(ctx context.Context, measurement string, sample datasource.Sample) error {
+	escaped := strings.ReplaceAll(strings.ReplaceAll(measurement, ",", "\\,"), " ", "\\ ")
+	line := fmt.Sprintf("%s value=%f %d\n", escaped, sample.Value, sample.Timestamp.UnixNano())
+
+	writeURL := fmt.Sprintf("%s/write?db=%s", w.addr, url.QueryEscape(w.database))
+	req, err := http.NewRequestWithContext(ctx, "POST", writeURL, strings.NewReader(line))
+	if err != nil {
+		return err
+	}
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error writing to influxdb: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("influxdb write returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/datasource"
+)
+
+// S3JSONL appends each sample as one JSON line to an S3 object
+// partitioned by measurement and UTC date (one object per
+// measurement/day), so historical values can be read back without
+// re-querying the upstream source. S3 has no real append, so each
+// Write rewrites the day's object with the new line tacked on; that's
+// fine at this tool's sample volume and keeps the read path trivial.
+type S3JSONL struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+type jsonlRecord struct {
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewS3JSONLFromEnv builds an S3JSONL sink from SINK_S3_BUCKET and
+// optional SINK_S3_PREFIX. It returns ok=false when the bucket is
+// unset.
+func NewS3JSONLFromEnv() (*S3JSONL, bool) {
+	bucket := os.Getenv("SINK_S3_BUCKET")
+	if bucket == "" {
+		return nil, false
+	}
+	return &S3JSONL{
+		client: s3.New(session.Must(session.NewSession())),
+		bucket: bucket,
+		prefix: strings.Trim(os.Getenv("SINK_S3_PREFIX"), "/"),
+	}, true
+}
+
+func (s *S3JSONL) Name() string { return "s3-jsonl" }
+
+func (s *S3JSONL) key(measurement string, day time.Time) string {
+	safe := strings.ReplaceAll(strings.ToLower(measurement), " ", "_")
+	key := fmt.Sprintf("%s/%s.jsonl", safe, day.UTC().Format("2006-01-02"))
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+	return key
+}
+
+func (s *S3JSONL) getObject(key string) ([]byte, error) {
+	result, err := s.client.GetObject(&s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	if err != nil {
+		if strings.Contains(err.Error(), "NoSuchKey") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(result.Body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *S3JSONL) Write(ctx context.Context, measurement string, sample datasource.Sample) error {
+	key := s.key(measurement, sample.Timestamp)
+
+	line, err := json.Marshal(jsonlRecord{Value: sample.Value, Timestamp: sample.Timestamp})
+	if err != nil {
+		return fmt.Errorf("error marshaling sample: %v", err)
+	}
+	line = append(line, '\n')
+
+	existing, err := s.getObject(key)
+	if err != nil {
+		return fmt.Errorf("error reading existing partition %s: %v", key, err)
+	}
+
+	_, err = s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(append(existing, line...)),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing partition %s: %v", key, err)
+	}
+	return nil
+}
+
+// Query reads back every sample recorded for measurement between from
+// and to, one day-partition at a time.
+func (s *S3JSONL) Query(ctx context.Context, measurement string, from, to time.Time) ([]datasource.Sample, error) {
+	var samples []datasource.Sample
+
+	for day := from.UTC().Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		key := s.key(measurement, day)
+		data, err := s.getObject(key)
+		if err != nil {
+			return nil, fmt.Errorf("error reading partition %s: %v", key, err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var rec jsonlRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue
+			}
+			if rec.Timestamp.Before(from) || rec.Timestamp.After(to) {
+				continue
+			}
+			samples = append(samples, datasource.Sample{Measurement: measurement, Value: rec.Value, Timestamp: rec.Timestamp})
+		}
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,40 @@
+// Package sink persists raw samples fetched from a DataSource so they
+// can be read back later, independent of the notifier/threshold
+// pipeline that decides whether to alert on them.
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tricarico1/go_watershed/internal/watershed/datasource"
+	"github.com/Tricarico1/go_watershed/internal/watershed/logging"
+)
+
+var log = logging.Logger
+
+// Sink persists a single sample for a measurement.
+type Sink interface {
+	// Name identifies the sink, e.g. "s3-jsonl" or "parquet".
+	Name() string
+	Write(ctx context.Context, measurement string, sample datasource.Sample) error
+}
+
+// QueryableSink is implemented by sinks that can also serve samples
+// back out, for the local debug API. Write-only sinks, like the
+// InfluxDB line-protocol writer, don't implement it.
+type QueryableSink interface {
+	Sink
+	Query(ctx context.Context, measurement string, from, to time.Time) ([]datasource.Sample, error)
+}
+
+// Queryable returns the first sink in sinks that supports Query, or
+// ok=false if none do.
+func Queryable(sinks []Sink) (QueryableSink, bool) {
+	for _, s := range sinks {
+		if qs, ok := s.(QueryableSink); ok {
+			return qs, true
+		}
+	}
+	return nil, false
+}